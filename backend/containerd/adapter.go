@@ -0,0 +1,163 @@
+// Package containerd implements an ephemerald.Adapter backed by
+// containerd's execution service instead of a Docker daemon, for
+// rootless/CI environments where a Docker socket isn't available.
+//
+// Selection happens via the "backend" key in the pool JSON config
+// (config.Parse), which defaults to "docker" for compatibility; set it to
+// "containerd" to use this adapter instead.
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	containerd "github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/oci"
+	"github.com/docker/docker/api/types"
+
+	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/params"
+)
+
+const defaultNamespace = "ephemerald"
+
+// Config configures the containerd adapter.
+type Config struct {
+	// Address is the containerd gRPC socket, e.g. /run/containerd/containerd.sock.
+	Address string
+	// Namespace isolates ephemerald's containers from other containerd clients.
+	Namespace string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Address:   "/run/containerd/containerd.sock",
+		Namespace: defaultNamespace,
+	}
+}
+
+type adapter struct {
+	client *containerd.Client
+	log    logrus.FieldLogger
+}
+
+// New dials containerd and returns an adapter suitable for
+// ephemerald.NewPool's backend-neutral Adapter.
+func New(log logrus.FieldLogger, cfg Config) (ephemerald.Adapter, error) {
+	client, err := containerd.New(cfg.Address, containerd.WithDefaultNamespace(cfg.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %v", err)
+	}
+
+	return &adapter{
+		client: client,
+		log:    log.WithField("component", "containerd-adapter"),
+	}, nil
+}
+
+// MakeParams builds connection params for a container started through this
+// adapter from the ports ephemerald.TCPPortsFor finds in Status. This
+// adapter attaches no CNI network and publishes no ports, and Status
+// reports none, so MakeParams returns a "no published ports" error for
+// every containerd-backed item.
+func (a *adapter) MakeParams(c ephemerald.PoolContainer) (params.Params, error) {
+	ports := ephemerald.TCPPortsFor(c.Status())
+	if len(ports) == 0 {
+		return params.Params{}, fmt.Errorf("containerd: no published ports for %v", c.ID())
+	}
+
+	keys := make([]string, 0, len(ports))
+	for k := range ports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return params.Params{Url: fmt.Sprintf("tcp://localhost:%v", ports[keys[0]])}, nil
+}
+
+// Create pulls image if needed and creates (but doesn't start) a
+// container and its task via containerd's execution service directly,
+// returning the container id.
+func (a *adapter) Create(ctx context.Context, ref string) (string, error) {
+	image, err := a.client.Pull(ctx, ref, containerd.WithPullUnpack)
+	if err != nil {
+		return "", fmt.Errorf("containerd: pull %v: %v", ref, err)
+	}
+
+	id := fmt.Sprintf("ephemerald-%v", ref)
+
+	container, err := a.client.NewContainer(ctx, id,
+		containerd.WithNewSnapshot(id+"-snapshot", image),
+		containerd.WithNewSpec(oci.WithImageConfig(image)),
+	)
+	if err != nil {
+		return "", fmt.Errorf("containerd: create %v: %v", ref, err)
+	}
+
+	if _, err := container.NewTask(ctx, cio.NullIO); err != nil {
+		return "", fmt.Errorf("containerd: create task for %v: %v", id, err)
+	}
+
+	return id, nil
+}
+
+// Start starts the task created by Create.
+func (a *adapter) Start(ctx context.Context, id string) error {
+	task, err := a.loadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return task.Start(ctx)
+}
+
+// Stop kills the task created by Create.
+func (a *adapter) Stop(ctx context.Context, id string) error {
+	task, err := a.loadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return task.Kill(ctx, 15) // SIGTERM
+}
+
+// Wait blocks until the task created by Create exits.
+func (a *adapter) Wait(ctx context.Context, id string) error {
+	task, err := a.loadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	statusch, err := task.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := <-statusch
+	return status.Error()
+}
+
+func (a *adapter) loadTask(ctx context.Context, id string) (containerd.Task, error) {
+	container, err := a.client.LoadContainer(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("containerd: load %v: %v", id, err)
+	}
+	return container.Task(ctx, nil)
+}
+
+// Status returns a minimal docker-shaped inspect result for id.
+// containerd has no notion of published ports -- that's a CNI/network
+// plugin concern this adapter doesn't drive -- so NetworkSettings is
+// always empty here, and ephemerald.TCPPortsFor correctly reports no
+// ports for a containerd-backed item rather than this adapter
+// fabricating ones that aren't real.
+func (a *adapter) Status(ctx context.Context, id string) (types.ContainerJSON, error) {
+	if _, err := a.client.LoadContainer(ctx, id); err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("containerd: load %v: %v", id, err)
+	}
+
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: id},
+	}, nil
+}
@@ -0,0 +1,122 @@
+// Package docker implements an ephemerald.Adapter backed by a Docker
+// daemon. It's the default backend -- config.Parse's "backend" key
+// only needs to be set to pick containerd or podman instead.
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+
+	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/params"
+)
+
+// Config configures the docker adapter.
+type Config struct {
+	// Host is the docker daemon socket, e.g. unix:///var/run/docker.sock.
+	// Empty uses the client's own DOCKER_HOST/default-socket resolution.
+	Host string
+}
+
+func DefaultConfig() Config {
+	return Config{}
+}
+
+type adapter struct {
+	client *client.Client
+	log    logrus.FieldLogger
+}
+
+// New returns an adapter that talks to a Docker daemon.
+func New(log logrus.FieldLogger, cfg Config) (ephemerald.Adapter, error) {
+	opts := []func(*client.Client) error{client.FromEnv}
+	if cfg.Host != "" {
+		opts = append(opts, client.WithHost(cfg.Host))
+	}
+
+	cl, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("docker: %v", err)
+	}
+
+	return &adapter{
+		client: cl,
+		log:    log.WithField("component", "docker-adapter"),
+	}, nil
+}
+
+// MakeParams builds connection params for a container started through
+// this adapter, from whatever ports Docker published for it.
+func (a *adapter) MakeParams(c ephemerald.PoolContainer) (params.Params, error) {
+	ports := ephemerald.TCPPortsFor(c.Status())
+	if len(ports) == 0 {
+		return params.Params{}, fmt.Errorf("docker: no published ports for %v", c.ID())
+	}
+
+	keys := make([]string, 0, len(ports))
+	for k := range ports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return params.Params{Url: fmt.Sprintf("tcp://localhost:%v", ports[keys[0]])}, nil
+}
+
+// Create pulls image if needed and creates (but doesn't start) a
+// container for it, returning the container id.
+func (a *adapter) Create(ctx context.Context, image string) (string, error) {
+	reader, err := a.client.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return "", fmt.Errorf("docker: pull %v: %v", image, err)
+	}
+	io.Copy(ioutil.Discard, reader)
+	reader.Close()
+
+	resp, err := a.client.ContainerCreate(ctx, &container.Config{Image: image}, nil, nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("docker: create %v: %v", image, err)
+	}
+
+	return resp.ID, nil
+}
+
+// Start starts the container created by Create.
+func (a *adapter) Start(ctx context.Context, id string) error {
+	return a.client.ContainerStart(ctx, id, types.ContainerStartOptions{})
+}
+
+// Stop stops the container created by Create.
+func (a *adapter) Stop(ctx context.Context, id string) error {
+	return a.client.ContainerStop(ctx, id, nil)
+}
+
+// Wait blocks until the container created by Create exits.
+func (a *adapter) Wait(ctx context.Context, id string) error {
+	statusch, errch := a.client.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case err := <-errch:
+		return err
+	case status := <-statusch:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("docker: %v: exited %v", id, status.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Status returns Docker's own inspect result for id.
+func (a *adapter) Status(ctx context.Context, id string) (types.ContainerJSON, error) {
+	out, err := a.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("docker: inspect %v: %v", id, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+// Package podman implements an ephemerald.Adapter backed by the podman
+// REST API instead of a Docker daemon, for rootless/CI environments where
+// a Docker socket isn't available.
+//
+// Selection happens via the "backend" key in the pool JSON config
+// (config.Parse), which defaults to "docker" for compatibility; set it to
+// "podman" to use this adapter instead.
+package podman
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types"
+
+	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/params"
+)
+
+// Config configures the podman adapter.
+type Config struct {
+	// BaseURL is the podman REST API endpoint, e.g.
+	// unix:///run/podman/podman.sock or http://localhost:8080.
+	BaseURL string
+}
+
+func DefaultConfig() Config {
+	return Config{BaseURL: "unix:///run/podman/podman.sock"}
+}
+
+type adapter struct {
+	client  *http.Client
+	baseURL string
+	log     logrus.FieldLogger
+}
+
+// New returns an adapter that talks to podman over its REST API.
+func New(log logrus.FieldLogger, cfg Config) (ephemerald.Adapter, error) {
+	client := &http.Client{}
+	baseURL := cfg.BaseURL
+
+	if strings.HasPrefix(baseURL, "unix://") {
+		sock := strings.TrimPrefix(baseURL, "unix://")
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sock)
+			},
+		}
+		baseURL = "http://localhost"
+	}
+
+	return &adapter{
+		client:  client,
+		baseURL: baseURL,
+		log:     log.WithField("component", "podman-adapter"),
+	}, nil
+}
+
+// MakeParams builds connection params for a container started through this
+// adapter. Podman's REST API reports port bindings in the same shape as
+// Docker's, so ephemerald.TCPPortsFor works unmodified.
+func (a *adapter) MakeParams(c ephemerald.PoolContainer) (params.Params, error) {
+	ports := ephemerald.TCPPortsFor(c.Status())
+	if len(ports) == 0 {
+		return params.Params{}, fmt.Errorf("podman: no published ports for %v", c.ID())
+	}
+
+	keys := make([]string, 0, len(ports))
+	for k := range ports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return params.Params{Url: fmt.Sprintf("tcp://localhost:%v", ports[keys[0]])}, nil
+}
+
+// Create creates (but doesn't start) a container via podman's
+// Docker-compatible REST endpoints directly, returning the container id.
+func (a *adapter) Create(ctx context.Context, image string) (string, error) {
+	body, _ := json.Marshal(struct {
+		Image string `json:"image"`
+	}{image})
+
+	var created struct {
+		Id string `json:"Id"`
+	}
+	if err := a.post(ctx, "/containers/create", body, &created); err != nil {
+		return "", fmt.Errorf("podman: create %v: %v", image, err)
+	}
+
+	return created.Id, nil
+}
+
+// Start starts the container created by Create.
+func (a *adapter) Start(ctx context.Context, id string) error {
+	return a.post(ctx, "/containers/"+id+"/start", nil, nil)
+}
+
+// Stop stops the container created by Create.
+func (a *adapter) Stop(ctx context.Context, id string) error {
+	return a.post(ctx, "/containers/"+id+"/stop", nil, nil)
+}
+
+// Wait blocks until the container created by Create exits.
+func (a *adapter) Wait(ctx context.Context, id string) error {
+	return a.post(ctx, "/containers/"+id+"/wait", nil, nil)
+}
+
+func (a *adapter) post(ctx context.Context, path string, body []byte, out interface{}) error {
+	return a.do(ctx, "POST", path, body, out)
+}
+
+func (a *adapter) get(ctx context.Context, path string, out interface{}) error {
+	return a.do(ctx, "GET", path, nil, out)
+}
+
+func (a *adapter) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, a.baseURL+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("podman: %v: unexpected status %v", path, resp.StatusCode)
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// Status returns podman's inspect result for id, which it reports in
+// the same docker-compatible shape Docker's own /containers/{id}/json
+// does, so ephemerald.TCPPortsFor works unmodified against it.
+func (a *adapter) Status(ctx context.Context, id string) (types.ContainerJSON, error) {
+	var out types.ContainerJSON
+	if err := a.get(ctx, "/containers/"+id+"/json", &out); err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("podman: inspect %v: %v", id, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,34 @@
+// Package backend resolves a config.Config's Backend name to the
+// ephemerald.Adapter that creates its containers. It's a separate
+// package from config and from ephemerald itself because every
+// backend/* adapter package imports ephemerald for the Adapter/
+// PoolContainer types, and ephemerald can't import any of them back
+// without a cycle -- so the dispatch has to live somewhere that can
+// import all three without ephemerald importing this package.
+package backend
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+
+	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/backend/containerd"
+	"github.com/boz/ephemerald/backend/docker"
+	"github.com/boz/ephemerald/backend/podman"
+)
+
+// Select returns the Adapter for the given backend name ("docker",
+// "containerd", or "podman"), using each adapter's own DefaultConfig.
+func Select(log logrus.FieldLogger, name string) (ephemerald.Adapter, error) {
+	switch name {
+	case "", "docker":
+		return docker.New(log, docker.DefaultConfig())
+	case "containerd":
+		return containerd.New(log, containerd.DefaultConfig())
+	case "podman":
+		return podman.New(log, podman.DefaultConfig())
+	default:
+		return nil, fmt.Errorf("backend: unknown backend %q", name)
+	}
+}
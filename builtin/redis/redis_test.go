@@ -25,9 +25,12 @@ func TestActionExec(t *testing.T) {
 func TestActionTruncate(t *testing.T) {
 	testutil.WithPoolFromFile(t, "pool.json", func(pool ephemerald.Pool) {
 		func() {
-			p, err := pool.Checkout()
+			item, _, err := pool.Checkout()
+			require.NoError(t, err)
+			defer pool.Return(item)
+
+			p, err := item.Params()
 			require.NoError(t, err)
-			defer pool.Return(p)
 
 			db, err := rredis.DialURL(p.Url)
 			require.NoError(t, err)
@@ -38,9 +41,12 @@ func TestActionTruncate(t *testing.T) {
 		}()
 
 		func() {
-			p, err := pool.Checkout()
+			item, _, err := pool.Checkout()
+			require.NoError(t, err)
+			defer pool.Return(item)
+
+			p, err := item.Params()
 			require.NoError(t, err)
-			defer pool.Return(p)
 
 			db, err := rredis.DialURL(p.Url)
 			require.NoError(t, err)
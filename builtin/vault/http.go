@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/boz/ephemerald/lifecycle"
+)
+
+const (
+	defaultRetries = 10
+	defaultTimeout = 5 * time.Second
+	defaultDelay   = time.Second
+)
+
+// vaultRequest issues method against url/path, marshaling body (if given) as
+// the JSON request body and unmarshaling the JSON response into out (if
+// given). It's shared by the vault.* lifecycle actions, which otherwise
+// only differ in the path, method, token and body they send.
+func vaultRequest(e lifecycle.Env, method, url, path, token string, body, out interface{}) error {
+	var reader *bytes.Reader
+
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(buf)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		e.Log().WithField("status", resp.StatusCode).Debug("ERROR: vault request")
+		return fmt.Errorf("vault: %v: %s", resp.StatusCode, buf)
+	}
+
+	if out == nil || len(buf) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(buf, out)
+}
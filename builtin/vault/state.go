@@ -0,0 +1,69 @@
+package vault
+
+import (
+	"sync"
+
+	"github.com/boz/ephemerald/params"
+)
+
+// vaultState is what vault.init discovers about a freshly initialized
+// server that vault.unseal/vault.mount/vault.policy need afterwards.
+// Actions only get lifecycle.Env and params.Params, and neither has
+// anywhere to stash data between one action's Do and the next, so state
+// is kept here instead, keyed by the container's URL -- stable for the
+// life of a container, and overwritten each time vault.init reruns it.
+type vaultState struct {
+	RootToken  string
+	UnsealKeys []string
+}
+
+var (
+	stateMu sync.Mutex
+	state   = make(map[string]*vaultState)
+)
+
+func getState(p params.Params) *vaultState {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, ok := state[p.Url]
+	if !ok {
+		s = &vaultState{}
+		state[p.Url] = s
+	}
+	return s
+}
+
+// RootToken returns the root token vault.init discovered for the server at
+// p, so test code can use it to DialURL against a ready-to-use Vault
+// instead of going through the unseal/mount/policy actions itself. It's
+// empty until vault.init has run against p.
+func RootToken(p params.Params) string {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, ok := state[p.Url]
+	if !ok {
+		return ""
+	}
+	return s.RootToken
+}
+
+// UnsealKeys returns the unseal keys vault.init discovered for the server
+// at p. It's empty until vault.init has run against p.
+//
+// The returned slice is a copy, so the caller can freely sort or mutate
+// it without corrupting the state vault.unseal itself reads from.
+func UnsealKeys(p params.Params) []string {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, ok := state[p.Url]
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, len(s.UnsealKeys))
+	copy(keys, s.UnsealKeys)
+	return keys
+}
@@ -130,15 +130,31 @@ type pool struct {
 }
 
 func (p *pool) Checkout() (Item, error) {
-	item, err := p.parent.Checkout()
+	poolItem, _, err := p.parent.Checkout()
 	if err != nil {
 		return nil, err
 	}
-	return NewItem(item), nil
+	return &checkoutItem{item: *NewItem(poolItem).(*item), poolItem: poolItem}, nil
+}
+func (p *pool) Return(i Item) {
+	c, ok := i.(*checkoutItem)
+	if !ok {
+		// i wasn't handed out by Checkout (e.g. a caller built its own
+		// Item some other way), so there's no ephemerald.PoolItem to
+		// release it back to the parent pool with.
+		return
+	}
+	p.parent.Return(c.poolItem)
 }
-func (p *pool) Return(item Item) {
-	p.parent.Return(item)
+
+// checkoutItem is the Item Pool.Checkout hands out: an item plus the
+// ephemerald.PoolItem backing it, so Pool.Return can give it back to
+// the parent pool's free list (which releases its checkout lease).
+type checkoutItem struct {
+	item
+	poolItem ephemerald.PoolItem
 }
+
 func (p *pool) WaitReady() error {
 	return p.parent.WaitReady()
 }
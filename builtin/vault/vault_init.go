@@ -0,0 +1,65 @@
+package vault
+
+import (
+	"encoding/json"
+
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/params"
+)
+
+func init() {
+	lifecycle.MakeActionPlugin("vault.init", actionInitParse)
+}
+
+func actionInitParse(buf []byte) (lifecycle.Action, error) {
+	action := &actionInit{
+		ActionConfig: lifecycle.ActionConfig{
+			Retries: defaultRetries,
+			Timeout: defaultTimeout,
+			Delay:   defaultDelay,
+		},
+		SecretShares:    1,
+		SecretThreshold: 1,
+	}
+	return action, json.Unmarshal(buf, action)
+}
+
+// actionInit initializes a fresh vault server (with a single unseal key
+// unless secret_shares says otherwise), storing the root token and unseal
+// keys in this package's per-URL state (see getState) so later actions
+// (vault.unseal, vault.mount, vault.policy) and test code (via RootToken
+// and UnsealKeys) can use them. Neither is added to params.Params.
+type actionInit struct {
+	lifecycle.ActionConfig
+	SecretShares    int `json:"secret_shares"`
+	SecretThreshold int `json:"secret_threshold"`
+}
+
+type initRequest struct {
+	SecretShares    int `json:"secret_shares"`
+	SecretThreshold int `json:"secret_threshold"`
+}
+
+type initResponse struct {
+	Keys      []string `json:"keys"`
+	RootToken string   `json:"root_token"`
+}
+
+func (a *actionInit) Do(e lifecycle.Env, p params.Params) error {
+	req := initRequest{
+		SecretShares:    a.SecretShares,
+		SecretThreshold: a.SecretThreshold,
+	}
+
+	var resp initResponse
+	if err := vaultRequest(e, "PUT", p.Url, "/v1/sys/init", "", req, &resp); err != nil {
+		e.Log().WithError(err).Debug("ERROR: vault init")
+		return err
+	}
+
+	s := getState(p)
+	s.RootToken = resp.RootToken
+	s.UnsealKeys = resp.Keys
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package vault
+
+import (
+	"encoding/json"
+
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/params"
+)
+
+func init() {
+	lifecycle.MakeActionPlugin("vault.mount", actionMountParse)
+}
+
+func actionMountParse(buf []byte) (lifecycle.Action, error) {
+	action := &actionMount{
+		ActionConfig: lifecycle.ActionConfig{
+			Retries: defaultRetries,
+			Timeout: defaultTimeout,
+			Delay:   defaultDelay,
+		},
+	}
+	return action, json.Unmarshal(buf, action)
+}
+
+// actionMount enables a secret or auth backend at Path, using Type and the
+// raw Config blob as the mount's configuration.
+type actionMount struct {
+	lifecycle.ActionConfig
+	Path   string          `json:"path"`
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config"`
+}
+
+func (a *actionMount) Do(e lifecycle.Env, p params.Params) error {
+	req := struct {
+		Type   string          `json:"type"`
+		Config json.RawMessage `json:"config,omitempty"`
+	}{a.Type, a.Config}
+
+	if err := vaultRequest(e, "POST", p.Url, "/v1/sys/mounts/"+a.Path, getState(p).RootToken, req, nil); err != nil {
+		e.Log().WithError(err).Debug("ERROR: vault mount")
+		return err
+	}
+
+	return nil
+}
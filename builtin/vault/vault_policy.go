@@ -0,0 +1,45 @@
+package vault
+
+import (
+	"encoding/json"
+
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/params"
+)
+
+func init() {
+	lifecycle.MakeActionPlugin("vault.policy", actionPolicyParse)
+}
+
+func actionPolicyParse(buf []byte) (lifecycle.Action, error) {
+	action := &actionPolicy{
+		ActionConfig: lifecycle.ActionConfig{
+			Retries: defaultRetries,
+			Timeout: defaultTimeout,
+			Delay:   defaultDelay,
+		},
+	}
+	return action, json.Unmarshal(buf, action)
+}
+
+// actionPolicy writes a named policy, given as an HCL or JSON rules
+// document, to a ready vault server.
+type actionPolicy struct {
+	lifecycle.ActionConfig
+	Name  string `json:"name"`
+	Rules string `json:"rules"`
+}
+
+func (a *actionPolicy) Do(e lifecycle.Env, p params.Params) error {
+	req := struct {
+		Rules string `json:"rules"`
+	}{a.Rules}
+
+	token := getState(p).RootToken
+	if err := vaultRequest(e, "PUT", p.Url, "/v1/sys/policy/"+a.Name, token, req, nil); err != nil {
+		e.Log().WithError(err).Debug("ERROR: vault policy")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/params"
+)
+
+func init() {
+	lifecycle.MakeActionPlugin("vault.unseal", actionUnsealParse)
+}
+
+func actionUnsealParse(buf []byte) (lifecycle.Action, error) {
+	action := &actionUnseal{
+		ActionConfig: lifecycle.ActionConfig{
+			Retries: defaultRetries,
+			Timeout: defaultTimeout,
+			Delay:   defaultDelay,
+		},
+	}
+	return action, json.Unmarshal(buf, action)
+}
+
+// actionUnseal submits the unseal keys vault.init stored in this package's
+// per-URL state,
+// one at a time, until the server reports sealed: false.
+type actionUnseal struct {
+	lifecycle.ActionConfig
+}
+
+type unsealRequest struct {
+	Key string `json:"key"`
+}
+
+type unsealResponse struct {
+	Sealed bool `json:"sealed"`
+}
+
+func (a *actionUnseal) Do(e lifecycle.Env, p params.Params) error {
+	keys := getState(p).UnsealKeys
+	if len(keys) == 0 {
+		return fmt.Errorf("vault: no unseal keys available (did vault.init run first?)")
+	}
+
+	var resp unsealResponse
+	for _, key := range keys {
+		req := unsealRequest{Key: key}
+		if err := vaultRequest(e, "PUT", p.Url, "/v1/sys/unseal", "", req, &resp); err != nil {
+			e.Log().WithError(err).Debug("ERROR: vault unseal")
+			return err
+		}
+
+		if !resp.Sealed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("vault: still sealed after submitting %v key(s)", len(keys))
+}
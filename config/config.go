@@ -0,0 +1,134 @@
+// Package config parses the pool JSON config files ephemerald's main
+// package and testutil.WithPoolFromFile load pools from.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/ui"
+)
+
+// defaultBackend is used when a pool config omits "backend", so configs
+// written before backend/containerd and backend/podman existed keep
+// behaving the same.
+const defaultBackend = "docker"
+
+const defaultSize = 1
+
+// Port is one port a pool's containers publish.
+type Port struct {
+	Proto string `json:"proto"`
+	Port  int    `json:"port"`
+}
+
+// Config is a single pool's configuration, parsed from one pool JSON
+// config object.
+//
+// Backend picks which ephemerald.Adapter builds this pool's containers
+// ("docker", the default; "containerd"; or "podman"), so rootless/CI
+// environments without a Docker socket can still run ephemerald --
+// resolving the name to an Adapter lives in package backend, not here,
+// since doing it here would make config import every backend/* package.
+type Config struct {
+	Name    string
+	Backend string
+	Size    int
+	Image   string
+	Ports   []Port
+	Env     map[string]string
+
+	Lifecycle lifecycle.Manager
+}
+
+type rawConfig struct {
+	Name    string            `json:"name"`
+	Backend string            `json:"backend"`
+	Size    int               `json:"size"`
+	Image   string            `json:"image"`
+	Ports   []Port            `json:"ports"`
+	Env     map[string]string `json:"env"`
+
+	Healthcheck []json.RawMessage `json:"healthcheck"`
+	Initialize  []json.RawMessage `json:"initialize"`
+	Reset       []json.RawMessage `json:"reset"`
+}
+
+// Parse reads a single pool config from buf. name is used as the pool's
+// name when the config doesn't set its own (testutil.WithPoolFromFile
+// passes the test name, so pools from different tests don't collide).
+func Parse(log logrus.FieldLogger, emitter ui.Emitter, name string, buf []byte) (*Config, error) {
+	var raw rawConfig
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+
+	if raw.Name == "" {
+		raw.Name = name
+	}
+	if raw.Backend == "" {
+		raw.Backend = defaultBackend
+	}
+	if raw.Size <= 0 {
+		raw.Size = defaultSize
+	}
+
+	healthcheck, err := lifecycle.ParseStage(raw.Healthcheck)
+	if err != nil {
+		return nil, fmt.Errorf("config: healthcheck: %v", err)
+	}
+
+	initialize, err := lifecycle.ParseStage(raw.Initialize)
+	if err != nil {
+		return nil, fmt.Errorf("config: initialize: %v", err)
+	}
+
+	reset, err := lifecycle.ParseStage(raw.Reset)
+	if err != nil {
+		return nil, fmt.Errorf("config: reset: %v", err)
+	}
+
+	// emitter.ForPool(raw.Name) is where this config's pool/container
+	// updates would be tagged for a live UI; left unused until the rest
+	// of ui.UI's event plumbing exists to consume it.
+	_ = emitter
+
+	return &Config{
+		Name:      raw.Name,
+		Backend:   raw.Backend,
+		Size:      raw.Size,
+		Image:     raw.Image,
+		Ports:     raw.Ports,
+		Env:       raw.Env,
+		Lifecycle: lifecycle.New(log.WithField("pool", raw.Name), healthcheck, initialize, reset),
+	}, nil
+}
+
+// Read parses every pool config out of f, which holds a JSON array of
+// the same object Parse takes one instance of.
+func Read(log logrus.FieldLogger, emitter ui.Emitter, f os.File) ([]*Config, error) {
+	buf, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(buf, &raws); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+
+	configs := make([]*Config, 0, len(raws))
+	for i, raw := range raws {
+		cfg, err := Parse(log, emitter, fmt.Sprintf("pool-%d", i), raw)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+
+	return configs, nil
+}
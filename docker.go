@@ -0,0 +1,89 @@
+package ephemerald
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+
+	"github.com/boz/ephemerald/params"
+)
+
+// dockerAdapter is the Adapter NewPool uses. It lives here rather than
+// in its own backend/docker package because every backend/* adapter
+// imports this package for the Adapter/PoolContainer types, so this
+// package can never import one of them back without a cycle.
+type dockerAdapter struct {
+	client *client.Client
+	log    logrus.FieldLogger
+}
+
+// NewDockerAdapter returns an Adapter that talks to a Docker daemon,
+// the same one NewPool uses. It's exported for callers building a pool
+// from a parsed config (see config.Parse) rather than an in-Go Config,
+// which need an Adapter to pass to NewPoolFromConfig.
+func NewDockerAdapter(log logrus.FieldLogger) (Adapter, error) {
+	cl, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, fmt.Errorf("docker: %v", err)
+	}
+
+	return &dockerAdapter{
+		client: cl,
+		log:    log.WithField("component", "docker-adapter"),
+	}, nil
+}
+
+func (a *dockerAdapter) MakeParams(c PoolContainer) (params.Params, error) {
+	return firstTCPParams(c, c.Status(), "docker")
+}
+
+func (a *dockerAdapter) Create(ctx context.Context, image string) (string, error) {
+	reader, err := a.client.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return "", fmt.Errorf("docker: pull %v: %v", image, err)
+	}
+	io.Copy(ioutil.Discard, reader)
+	reader.Close()
+
+	resp, err := a.client.ContainerCreate(ctx, &container.Config{Image: image}, nil, nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("docker: create %v: %v", image, err)
+	}
+
+	return resp.ID, nil
+}
+
+func (a *dockerAdapter) Start(ctx context.Context, id string) error {
+	return a.client.ContainerStart(ctx, id, types.ContainerStartOptions{})
+}
+
+func (a *dockerAdapter) Stop(ctx context.Context, id string) error {
+	return a.client.ContainerStop(ctx, id, nil)
+}
+
+func (a *dockerAdapter) Wait(ctx context.Context, id string) error {
+	statusch, errch := a.client.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case err := <-errch:
+		return err
+	case status := <-statusch:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("docker: %v: exited %v", id, status.StatusCode)
+		}
+		return nil
+	}
+}
+
+func (a *dockerAdapter) Status(ctx context.Context, id string) (types.ContainerJSON, error) {
+	out, err := a.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("docker: inspect %v: %v", id, err)
+	}
+	return out, nil
+}
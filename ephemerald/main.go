@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	stdnet "net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -9,12 +11,18 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/backend"
 	"github.com/boz/ephemerald/config"
 	"github.com/boz/ephemerald/net"
+	"github.com/boz/ephemerald/net/rpc"
+	"github.com/boz/ephemerald/net/rpc/pb"
 	"github.com/boz/ephemerald/ui"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 
 	_ "github.com/boz/ephemerald/builtin/postgres"
 	_ "github.com/boz/ephemerald/builtin/redis"
+	_ "github.com/boz/ephemerald/builtin/vault"
 
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
@@ -36,11 +44,62 @@ var (
 		Default("/dev/null").
 		OpenFile(os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
 
-	useGUI = kingpin.Flag("gui", "terminal gui output").
-		Default("true").
-		Bool()
+	uiMode = kingpin.Flag("ui", "output mode").
+		Default("gui").
+		Enum("gui", "io", "json")
+
+	metricsPort = kingpin.Flag("metrics-port", "Listen port for /metrics and /healthz").
+			Default("9216").
+			Int()
+
+	grpcPort = kingpin.Flag("grpc-port", "Listen port for the gRPC control-plane API").
+			Default("9217").
+			Int()
 )
 
+func serveGRPC(log logrus.FieldLogger, pools ephemerald.PoolSet, port int) {
+	addr := ":" + strconv.Itoa(port)
+
+	lis, err := stdnet.Listen("tcp", addr)
+	if err != nil {
+		log.WithError(err).Error("can't start gRPC listener")
+		return
+	}
+
+	srv := grpc.NewServer()
+	pb.RegisterPoolServiceServer(srv, rpc.NewServer(log, pools))
+
+	log.WithField("addr", addr).Info("serving gRPC")
+
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			log.WithError(err).Error("gRPC server exited")
+		}
+	}()
+}
+
+func serveMetrics(log logrus.FieldLogger, port int, events *ui.SSEHandler) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/lease/extend", net.LeaseHandler())
+	if events != nil {
+		mux.Handle("/events", events)
+	}
+
+	addr := ":" + strconv.Itoa(port)
+	log.WithField("addr", addr).Info("serving metrics")
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.WithError(err).Error("metrics server exited")
+		}
+	}()
+}
+
 func main() {
 	kingpin.Parse()
 
@@ -54,20 +113,34 @@ func main() {
 	ctx := context.Background()
 
 	var appui ui.UI
+	var events *ui.SSEHandler
 
-	if *useGUI {
-		appui = ui.NewGUI()
-	} else {
+	switch *uiMode {
+	case "io":
 		appui = ui.NewIOUI(os.Stdout)
+	case "json":
+		appui, events = ui.NewJSONEmitterWithSSE(os.Stdout)
+	default:
+		appui = ui.NewGUI()
 	}
 
 	configs, err := config.Read(log, appui.Emitter(), *configFile)
 	(*configFile).Close()
 	kingpin.FatalIfError(err, "invalid config file")
 
-	pools, err := ephemerald.NewPoolSet(log, ctx, configs)
+	poolConfigs := make([]ephemerald.PoolConfig, len(configs))
+	for i, cfg := range configs {
+		adapter, err := backend.Select(log, cfg.Backend)
+		kingpin.FatalIfError(err, "resolving pool backend")
+		poolConfigs[i] = ephemerald.PoolConfig{Config: cfg, Adapter: adapter}
+	}
+
+	pools, err := ephemerald.NewPoolSet(log, ctx, poolConfigs)
 	kingpin.FatalIfError(err, "creating pools")
 
+	serveMetrics(log, *metricsPort, events)
+	serveGRPC(log, pools, *grpcPort)
+
 	builder := net.NewServerBuilder()
 
 	builder.WithPort(*listenPort)
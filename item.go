@@ -22,6 +22,12 @@ const (
 	eventPoolItemResetError poolItemEvent = "reset-error"
 	eventPoolItemReady      poolItemEvent = "ready"
 	eventPoolItemReadyError poolItemEvent = "ready-error"
+
+	// eventPoolItemLeaseExpired is raised when a checkout lease goes
+	// unrenewed past its TTL. It resets the item the same way
+	// eventPoolItemReset does, so a client that crashes without
+	// returning its item doesn't leak it forever.
+	eventPoolItemLeaseExpired poolItemEvent = "lease-expired"
 )
 
 type poolItem struct {
@@ -29,6 +35,13 @@ type poolItem struct {
 	adapter   Adapter
 	container PoolContainer
 
+	// leases tracks the checkout lease for this item: at most one is
+	// ever active at a time, since an item is only ever checked out to
+	// a single caller. Checkout creates it; the caller renews it with
+	// ExtendLease until Return releases it, or the reaper expires it
+	// and reclaims the item via LeaseExpired.
+	leases *leaseManager
+
 	events chan poolItemEvent
 	joinch chan (chan<- poolEvent)
 
@@ -42,22 +55,28 @@ type poolItem struct {
 	log logrus.FieldLogger
 }
 
-func createPoolItem(log logrus.FieldLogger, adapter Adapter, lifecycle lifecycle.Manager) (PoolItem, error) {
+func createPoolItem(log logrus.FieldLogger, adapter Adapter, lm lifecycle.Manager, image string) (PoolItem, error) {
 	log = log.WithField("component", "pool-item")
 
-	container, err := createPoolContainer(log, adapter)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	container, err := createPoolContainer(ctx, adapter, image)
 	if err != nil {
 		log.WithError(err).
 			Error("can't create container")
+		cancel()
 		return nil, err
 	}
 
 	log = lcid(log, container.ID())
 
-	ctx, cancel := context.WithCancel(context.Background())
+	lm = lm.ForContainer(container.ID())
+	if binder, ok := lm.(statusItemBinder); ok {
+		lm = binder.bindStatusItem(container)
+	}
 
 	item := &poolItem{
-		lifecycle: lifecycle.ForContainer(container.ID()),
+		lifecycle: lm,
 		adapter:   adapter,
 		container: container,
 		events:    make(chan poolItemEvent),
@@ -68,6 +87,11 @@ func createPoolItem(log logrus.FieldLogger, adapter Adapter, lifecycle lifecycle
 		log:       log,
 	}
 
+	item.leases = newLeaseManager(log, DefaultLeaseTTL, func(id LeaseID, itemID string) {
+		leaseRegistry.Delete(id)
+		item.LeaseExpired()
+	})
+
 	go item.run()
 
 	return item, nil
@@ -81,6 +105,28 @@ func (i *poolItem) Status() types.ContainerJSON {
 	return i.container.Status()
 }
 
+// State reports this item's place in the ready/checked-out lifecycle,
+// for net/rpc's List/Watch responses.
+func (i *poolItem) State() string {
+	select {
+	case <-i.exited:
+		return "exited"
+	default:
+	}
+
+	if i.leases.active(i.ID()) {
+		return "checked-out"
+	}
+	return "ready"
+}
+
+// Params returns the connection params a checkout caller needs to
+// reach this item, built the same way a healthcheck/initialize/reset
+// action's params are.
+func (i *poolItem) Params() (params.Params, error) {
+	return i.currentParams()
+}
+
 func (i *poolItem) Join(ch chan<- poolEvent) {
 	i.joinch <- ch
 }
@@ -93,6 +139,36 @@ func (i *poolItem) Reset() {
 	go i.sendEvent(eventPoolItemReset)
 }
 
+// LeaseExpired reclaims the item after its checkout lease has gone
+// unrenewed past its TTL.
+func (i *poolItem) LeaseExpired() {
+	go i.sendEvent(eventPoolItemLeaseExpired)
+}
+
+// Checkout starts a new checkout lease for this item and returns its id.
+// The caller must call ExtendLease (or the package-level ExtendLease, by
+// id) before the lease's TTL elapses, or the item will be reclaimed
+// (reset) out from under it. Pool.Checkout calls this once it takes an
+// item off the free list, and hands the returned id back to its own
+// caller alongside the item.
+func (i *poolItem) Checkout() LeaseID {
+	id := i.leases.Create(i.ID())
+	leaseRegistry.Store(id, i)
+	return id
+}
+
+// ExtendLease renews a lease returned by Checkout for another TTL period.
+func (i *poolItem) ExtendLease(id LeaseID) error {
+	return i.leases.Extend(id)
+}
+
+// ReleaseLease discards a lease returned by Checkout without reclaiming
+// the item, for the common case of an explicit Return().
+func (i *poolItem) ReleaseLease(id LeaseID) {
+	leaseRegistry.Delete(id)
+	i.leases.Release(id)
+}
+
 func (i *poolItem) Kill() {
 	go i.sendEvent(eventPoolItemKill)
 }
@@ -173,6 +249,8 @@ func (i *poolItem) runMainLoop(ch chan<- poolEvent) {
 				i.container.Stop()
 			case eventPoolItemReset:
 				i.do(i.onChildReset)
+			case eventPoolItemLeaseExpired:
+				i.do(i.onChildReset)
 			}
 
 		}
@@ -183,6 +261,7 @@ func (i *poolItem) drain() {
 	log := i.log.WithField("method", "drain")
 
 	defer close(i.events)
+	defer i.leases.Stop()
 
 	ch := make(chan bool)
 	go func() {
@@ -208,7 +287,8 @@ func (i *poolItem) onChildStarted() {
 			i.events <- eventPoolItemLiveError
 			return
 		}
-		if err := i.lifecycle.DoHealthcheck(i.ctx, params); err != nil {
+		err = i.lifecycle.DoHealthcheck(i.ctx, params)
+		if err != nil {
 			i.log.WithError(err).Error("error checking liveliness")
 			i.events <- eventPoolItemLiveError
 			return
@@ -224,7 +304,8 @@ func (i *poolItem) onChildLive() {
 			i.events <- eventPoolItemReadyError
 			return
 		}
-		if err := i.lifecycle.DoInitialize(i.ctx, params); err != nil {
+		err = i.lifecycle.DoInitialize(i.ctx, params)
+		if err != nil {
 			i.log.WithError(err).Error("error initializing")
 			i.events <- eventPoolItemReadyError
 			return
@@ -241,7 +322,8 @@ func (i *poolItem) onChildReset() {
 			i.events <- eventPoolItemResetError
 			return
 		}
-		if err := i.lifecycle.DoReset(i.ctx, params); err != nil {
+		err = i.lifecycle.DoReset(i.ctx, params)
+		if err != nil {
 			i.log.WithError(err).Error("error provisioning")
 			i.events <- eventPoolItemResetError
 			return
@@ -0,0 +1,175 @@
+package ephemerald
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// DefaultLeaseTTL is how long a checkout lease is valid for before it must
+// be renewed with Extend.
+const DefaultLeaseTTL = 30 * time.Second
+
+// defaultLeaseReapInterval is how often the lease reaper scans for expired
+// leases.
+const defaultLeaseReapInterval = 5 * time.Second
+
+// LeaseID identifies a single checkout lease.
+type LeaseID string
+
+// ErrLeaseNotFound is returned by Extend when the lease id is unknown, most
+// likely because it already expired and was reaped.
+var ErrLeaseNotFound = fmt.Errorf("lease not found")
+
+type lease struct {
+	id      LeaseID
+	itemID  string
+	expires time.Time
+}
+
+// leaseManager tracks one lease per outstanding checkout. If a lease isn't
+// renewed with Extend before it expires, the reaper invokes onExpire so the
+// pool can reclaim the item (driving it through eventPoolItemLeaseExpired
+// back to ready) instead of leaking it forever.
+type leaseManager struct {
+	mu     sync.Mutex
+	leases map[LeaseID]*lease
+	ttl    time.Duration
+
+	onExpire func(id LeaseID, itemID string)
+
+	donech chan bool
+	log    logrus.FieldLogger
+
+	counter uint64
+}
+
+func newLeaseManager(log logrus.FieldLogger, ttl time.Duration, onExpire func(id LeaseID, itemID string)) *leaseManager {
+	lm := &leaseManager{
+		leases:   make(map[LeaseID]*lease),
+		ttl:      ttl,
+		onExpire: onExpire,
+		donech:   make(chan bool),
+		log:      log.WithField("component", "lease-manager"),
+	}
+
+	go lm.run()
+
+	return lm
+}
+
+// Create starts a new lease for itemID and returns its id.
+func (lm *leaseManager) Create(itemID string) LeaseID {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	id := lm.nextID(itemID)
+	lm.leases[id] = &lease{
+		id:      id,
+		itemID:  itemID,
+		expires: time.Now().Add(lm.ttl),
+	}
+
+	return id
+}
+
+// Extend renews id for another TTL period.
+func (lm *leaseManager) Extend(id LeaseID) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	l, ok := lm.leases[id]
+	if !ok {
+		return ErrLeaseNotFound
+	}
+
+	l.expires = time.Now().Add(lm.ttl)
+	return nil
+}
+
+// Release discards id without triggering a reclaim, for the common case of
+// an explicit Return().
+func (lm *leaseManager) Release(id LeaseID) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	delete(lm.leases, id)
+}
+
+// active reports whether itemID has an outstanding lease: one not yet
+// released or reaped. A lease past its expiry still counts until the next
+// reap removes it.
+func (lm *leaseManager) active(itemID string) bool {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	for _, l := range lm.leases {
+		if l.itemID == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+func (lm *leaseManager) Stop() {
+	close(lm.donech)
+}
+
+func (lm *leaseManager) nextID(itemID string) LeaseID {
+	n := atomic.AddUint64(&lm.counter, 1)
+	return LeaseID(fmt.Sprintf("%s-%d", itemID, n))
+}
+
+func (lm *leaseManager) run() {
+	ticker := time.NewTicker(defaultLeaseReapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lm.donech:
+			return
+		case <-ticker.C:
+			lm.reap()
+		}
+	}
+}
+
+func (lm *leaseManager) reap() {
+	now := time.Now()
+
+	var expired []*lease
+
+	lm.mu.Lock()
+	for id, l := range lm.leases {
+		if now.After(l.expires) {
+			expired = append(expired, l)
+			delete(lm.leases, id)
+		}
+	}
+	lm.mu.Unlock()
+
+	for _, l := range expired {
+		lm.log.WithField("lease", l.id).WithField("item", l.itemID).
+			Warn("lease expired, reclaiming item")
+		lm.onExpire(l.id, l.itemID)
+	}
+}
+
+// leaseRegistry maps an outstanding LeaseID back to the poolItem that
+// created it, so a caller that only has a lease id (such as the HTTP
+// handler in net/lease.go) can still renew it without going through the
+// Pool that handed the lease out in the first place.
+var leaseRegistry sync.Map // LeaseID -> *poolItem
+
+// ExtendLease renews the lease id for whichever item created it. It
+// returns ErrLeaseNotFound if id is unknown, which is the common case when
+// it already expired and was reaped.
+func ExtendLease(id LeaseID) error {
+	v, ok := leaseRegistry.Load(id)
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	return v.(*poolItem).ExtendLease(id)
+}
@@ -0,0 +1,84 @@
+package ephemerald
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseManagerCreateExtendRelease(t *testing.T) {
+	log := logrus.New()
+
+	var expired []LeaseID
+	lm := newLeaseManager(log, time.Minute, func(id LeaseID, itemID string) {
+		expired = append(expired, id)
+	})
+	defer lm.Stop()
+
+	id := lm.Create("item-1")
+	assert.NotEmpty(t, id)
+	assert.True(t, lm.active("item-1"))
+
+	require.NoError(t, lm.Extend(id))
+
+	lm.Release(id)
+	assert.False(t, lm.active("item-1"))
+	assert.Equal(t, ErrLeaseNotFound, lm.Extend(id))
+
+	// Release never triggers onExpire.
+	assert.Empty(t, expired)
+}
+
+func TestLeaseManagerExtendUnknown(t *testing.T) {
+	log := logrus.New()
+	lm := newLeaseManager(log, time.Minute, func(LeaseID, string) {})
+	defer lm.Stop()
+
+	assert.Equal(t, ErrLeaseNotFound, lm.Extend(LeaseID("nope")))
+}
+
+func TestLeaseManagerReapExpiresUnrenewedLease(t *testing.T) {
+	log := logrus.New()
+
+	expiredch := make(chan LeaseID, 1)
+	lm := newLeaseManager(log, time.Millisecond, func(id LeaseID, itemID string) {
+		expiredch <- id
+	})
+	defer lm.Stop()
+
+	id := lm.Create("item-1")
+	time.Sleep(2 * time.Millisecond)
+
+	// Drive the reaper directly rather than waiting out
+	// defaultLeaseReapInterval, which would make this test take 5s+.
+	lm.reap()
+
+	select {
+	case got := <-expiredch:
+		assert.Equal(t, id, got)
+	default:
+		t.Fatal("expected onExpire to fire for an expired, unrenewed lease")
+	}
+
+	assert.False(t, lm.active("item-1"))
+	assert.Equal(t, ErrLeaseNotFound, lm.Extend(id))
+}
+
+func TestLeaseManagerReapSparesRenewedLease(t *testing.T) {
+	log := logrus.New()
+
+	lm := newLeaseManager(log, 50*time.Millisecond, func(id LeaseID, itemID string) {
+		t.Fatalf("onExpire fired for renewed lease %v", id)
+	})
+	defer lm.Stop()
+
+	id := lm.Create("item-1")
+	require.NoError(t, lm.Extend(id))
+
+	lm.reap()
+
+	assert.True(t, lm.active("item-1"))
+}
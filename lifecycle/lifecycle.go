@@ -0,0 +1,160 @@
+// Package lifecycle runs the healthcheck/initialize/reset actions
+// configured for a pool against a container once it's up, as plain
+// data parsed from a pool JSON config rather than Go closures (compare
+// ephemerald.ProvisionerBuilder, which is the programmatic equivalent
+// used by builtin/vault's Builder).
+//
+// Concrete action types (builtin/postgres's postgres.ping,
+// builtin/vault's vault.init/unseal/mount/policy, ...) register
+// themselves with MakeActionPlugin from an init() func, keyed by the
+// "type" field of their JSON config entry.
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/boz/ephemerald/params"
+)
+
+// ActionConfig holds the knobs shared by every action type, embedded by
+// each concrete action struct alongside its own fields.
+type ActionConfig struct {
+	Retries int           `json:"retries"`
+	Timeout time.Duration `json:"timeout"`
+	Delay   time.Duration `json:"delay"`
+}
+
+// Env is what an Action's Do gets besides the item's params. It's an
+// interface rather than a bare *logrus.Entry so actions don't need to
+// know how the manager running them is built.
+type Env interface {
+	Log() logrus.FieldLogger
+}
+
+// Action is a single configured step of a healthcheck, initialize, or
+// reset stage.
+type Action interface {
+	Do(Env, params.Params) error
+}
+
+// ActionParseFunc parses one JSON action entry into an Action, applying
+// its own defaults before unmarshaling over them. See
+// builtin/postgres/ping.go for a typical implementation.
+type ActionParseFunc func([]byte) (Action, error)
+
+var plugins = make(map[string]ActionParseFunc)
+
+// MakeActionPlugin registers a named action type for use in a pool
+// config's healthcheck/initialize/reset arrays. Call it from an init()
+// func in the package that implements the action.
+func MakeActionPlugin(name string, fn ActionParseFunc) {
+	plugins[name] = fn
+}
+
+type actionHeader struct {
+	Type string `json:"type"`
+}
+
+// Stage is one parsed healthcheck/initialize/reset array: the ordered
+// list of actions it runs, stopping at the first one that errors.
+type Stage struct {
+	actions []Action
+}
+
+// ParseStage parses each entry of raw via its "type" field and the
+// matching registered plugin.
+func ParseStage(raw []json.RawMessage) (Stage, error) {
+	actions := make([]Action, 0, len(raw))
+
+	for _, entry := range raw {
+		var hdr actionHeader
+		if err := json.Unmarshal(entry, &hdr); err != nil {
+			return Stage{}, err
+		}
+
+		fn, ok := plugins[hdr.Type]
+		if !ok {
+			return Stage{}, fmt.Errorf("lifecycle: unknown action type %q", hdr.Type)
+		}
+
+		action, err := fn(entry)
+		if err != nil {
+			return Stage{}, fmt.Errorf("lifecycle: %v: %v", hdr.Type, err)
+		}
+		actions = append(actions, action)
+	}
+
+	return Stage{actions: actions}, nil
+}
+
+func (s Stage) has() bool { return len(s.actions) > 0 }
+
+func (s Stage) run(ctx context.Context, log logrus.FieldLogger, p params.Params) error {
+	e := env{log: log}
+	for _, a := range s.actions {
+		if err := a.Do(e, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type env struct{ log logrus.FieldLogger }
+
+func (e env) Log() logrus.FieldLogger { return e.log }
+
+// Manager drives a single pool item through its configured
+// healthcheck/initialize/reset stages. ForContainer scopes a pool-wide
+// Manager to one container, matching createPoolItem's
+// lifecycle.ForContainer(container.ID()) call.
+type Manager interface {
+	ForContainer(containerID string) Manager
+
+	HasHealthcheck() bool
+	DoHealthcheck(ctx context.Context, p params.Params) error
+
+	HasInitialize() bool
+	DoInitialize(ctx context.Context, p params.Params) error
+
+	HasReset() bool
+	DoReset(ctx context.Context, p params.Params) error
+}
+
+type manager struct {
+	log logrus.FieldLogger
+
+	healthcheck Stage
+	initialize  Stage
+	reset       Stage
+}
+
+// New returns a Manager running the given stages, logging under log.
+func New(log logrus.FieldLogger, healthcheck, initialize, reset Stage) Manager {
+	return &manager{log: log, healthcheck: healthcheck, initialize: initialize, reset: reset}
+}
+
+func (m *manager) ForContainer(containerID string) Manager {
+	n := *m
+	n.log = m.log.WithField("container", containerID)
+	return &n
+}
+
+func (m *manager) HasHealthcheck() bool { return m.healthcheck.has() }
+func (m *manager) HasInitialize() bool  { return m.initialize.has() }
+func (m *manager) HasReset() bool       { return m.reset.has() }
+
+func (m *manager) DoHealthcheck(ctx context.Context, p params.Params) error {
+	return m.healthcheck.run(ctx, m.log.WithField("stage", "healthcheck"), p)
+}
+
+func (m *manager) DoInitialize(ctx context.Context, p params.Params) error {
+	return m.initialize.run(ctx, m.log.WithField("stage", "initialize"), p)
+}
+
+func (m *manager) DoReset(ctx context.Context, p params.Params) error {
+	return m.reset.run(ctx, m.log.WithField("stage", "reset"), p)
+}
@@ -0,0 +1,38 @@
+package net
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/boz/ephemerald"
+)
+
+type extendRequest struct {
+	LeaseID string `json:"lease_id"`
+}
+
+// LeaseHandler renews a checkout lease on each request, so integration
+// tests that crash mid-checkout don't leak containers. It's backed by
+// ephemerald's package-level lease registry rather than a specific pool,
+// since a lease id alone is enough to find the item that issued it. Mount
+// it at POST /lease/extend.
+func LeaseHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req extendRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := ephemerald.ExtendLease(ephemerald.LeaseID(req.LeaseID)); err != nil {
+			if err == ephemerald.ErrLeaseNotFound {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
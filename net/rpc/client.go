@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"github.com/boz/ephemerald/net/rpc/pb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Client is a strongly-typed alternative to the HTTP JSON client in net/,
+// backed by the gRPC PoolService.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  pb.PoolServiceClient
+}
+
+// Dial connects to a PoolService listening at addr.
+func Dial(addr string) (*Client, error) {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, rpc: pb.NewPoolServiceClient(conn)}, nil
+}
+
+// Close tears down the underlying gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// CheckoutResult is what Checkout hands back: the checked-out item's id,
+// the lease id the caller must renew (via ExtendLease) before it expires,
+// and the connection params for reaching the item.
+type CheckoutResult struct {
+	ItemID  string
+	LeaseID string
+	Params  map[string]string
+}
+
+// Checkout requests an item from the named pool.
+func (c *Client) Checkout(pool string) (CheckoutResult, error) {
+	resp, err := c.rpc.Checkout(context.Background(), &pb.CheckoutRequest{Pool: pool})
+	if err != nil {
+		return CheckoutResult{}, err
+	}
+	return CheckoutResult{ItemID: resp.ItemId, LeaseID: resp.LeaseId, Params: resp.Params}, nil
+}
+
+// Return releases itemID back to the named pool.
+func (c *Client) Return(pool, itemID string) error {
+	_, err := c.rpc.Return(context.Background(), &pb.ReturnRequest{Pool: pool, ItemId: itemID})
+	return err
+}
+
+// List returns the ids and states of every item in the named pool.
+func (c *Client) List(pool string) ([]*pb.Item, error) {
+	resp, err := c.rpc.List(context.Background(), &pb.ListRequest{Pool: pool})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// Kill forcibly tears down itemID in the named pool.
+func (c *Client) Kill(pool, itemID string) error {
+	_, err := c.rpc.Kill(context.Background(), &pb.KillRequest{Pool: pool, ItemId: itemID})
+	return err
+}
+
+// Watch subscribes to state-change events for the named pool.
+func (c *Client) Watch(pool string) (pb.PoolService_WatchClient, error) {
+	return c.rpc.Watch(context.Background(), &pb.WatchRequest{Pool: pool})
+}
@@ -0,0 +1,188 @@
+// Package rpc implements a gRPC control-plane API alongside the existing
+// HTTP server in net/, covering the same checkout/return/status surface of
+// ephemerald.Pool and ephemerald.PoolSet.
+//
+// The message and service definitions live in pool.proto; the generated
+// stubs are checked in under net/rpc/pb (run `go generate` to refresh them
+// after editing the .proto file).
+package rpc
+
+//go:generate protoc -I . --go_out=plugins=grpc:pb pool.proto
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/net/rpc/pb"
+	"golang.org/x/net/context"
+)
+
+// Server implements pb.PoolServiceServer on top of a PoolSet.
+type Server struct {
+	pools ephemerald.PoolSet
+	log   logrus.FieldLogger
+}
+
+// NewServer returns a gRPC PoolService backed by the given pool set.
+func NewServer(log logrus.FieldLogger, pools ephemerald.PoolSet) pb.PoolServiceServer {
+	return &Server{
+		pools: pools,
+		log:   log.WithField("component", "rpc-server"),
+	}
+}
+
+func (s *Server) pool(name string) (ephemerald.Pool, error) {
+	pool := s.pools.Pool(name)
+	if pool == nil {
+		return nil, ephemerald.ErrPoolNotFound
+	}
+	return pool, nil
+}
+
+func (s *Server) Checkout(ctx context.Context, req *pb.CheckoutRequest) (*pb.CheckoutResponse, error) {
+	pool, err := s.pool(req.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	item, leaseID, err := pool.Checkout()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := item.Params()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CheckoutResponse{
+		ItemId:  item.ID(),
+		LeaseId: string(leaseID),
+		Params:  map[string]string{"url": p.Url},
+	}, nil
+}
+
+func (s *Server) Return(ctx context.Context, req *pb.ReturnRequest) (*pb.ReturnResponse, error) {
+	pool, err := s.pool(req.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := pool.Item(req.ItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Return(item)
+	return &pb.ReturnResponse{}, nil
+}
+
+func (s *Server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	pool, err := s.pool(req.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListResponse{}
+	for _, item := range pool.Items() {
+		resp.Items = append(resp.Items, &pb.Item{
+			Id:    item.ID(),
+			State: item.State(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) Kill(ctx context.Context, req *pb.KillRequest) (*pb.KillResponse, error) {
+	pool, err := s.pool(req.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := pool.Item(req.ItemId)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Kill()
+	return &pb.KillResponse{}, nil
+}
+
+// Watch streams state changes for a single pool, mapping the pool's
+// ready/exit ephemerald.PoolEvent notifications onto pb.Event, until the
+// client cancels the call. Per-action lifecycle detail and ui's
+// pevent/cevent traffic are not sent, and stopping the pool does not end
+// the stream.
+func (s *Server) Watch(req *pb.WatchRequest, stream pb.PoolService_WatchServer) error {
+	pool, err := s.pool(req.Pool)
+	if err != nil {
+		return err
+	}
+
+	events, cancel := pool.Watch()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(eventToProto(req.Pool, e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Events is a bidirectional stream: the server pushes events for every
+// pool in the set, and the client may push Acks back on the same stream.
+func (s *Server) Events(stream pb.PoolService_EventsServer) error {
+	go drainAcks(stream)
+
+	events, cancel := s.pools.WatchAll()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(eventToProto(e.PoolName, e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func drainAcks(stream pb.PoolService_EventsServer) {
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return
+		}
+	}
+}
+
+func eventToProto(poolName string, e ephemerald.PoolEvent) *pb.Event {
+	return &pb.Event{
+		Id:             e.ID,
+		Pool:           poolName,
+		ItemId:         e.ItemID,
+		LifecycleName:  e.LifecycleName,
+		ActionName:     e.ActionName,
+		ActionAttempt:  int32(e.ActionAttempt),
+		ActionAttempts: int32(e.ActionAttempts),
+		Error:          errString(e.Err),
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
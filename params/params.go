@@ -0,0 +1,14 @@
+// Package params defines the connection parameters handed to lifecycle
+// actions and checkout callers once a pool item is up.
+package params
+
+// Params carries whatever a lifecycle action or checkout caller needs to
+// reach a running pool item. Today that's just the URL an adapter's
+// MakeParams built from the container's published ports (see
+// ephemerald.TCPPortsFor), but it's a struct rather than a bare string so
+// builtin lifecycle modules have somewhere to add fields without changing
+// every Adapter. builtin/vault does not add its root token here; it keeps
+// it in its own state, read back with vault.RootToken.
+type Params struct {
+	Url string
+}
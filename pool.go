@@ -0,0 +1,777 @@
+package ephemerald
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/boz/ephemerald/config"
+	"github.com/boz/ephemerald/lifecycle"
+	"github.com/boz/ephemerald/params"
+	"github.com/docker/docker/api/types"
+)
+
+// ErrPoolNotFound is returned by PoolSet.Pool's callers (net/rpc/server.go)
+// when asked for a pool name that isn't in the set.
+var ErrPoolNotFound = fmt.Errorf("pool not found")
+
+// containerEvent is raised on a PoolContainer's Events channel as its
+// underlying container moves through Create/Start/Wait.
+type containerEvent string
+
+const (
+	containerEventStarted     containerEvent = "started"
+	containerEventStartFailed containerEvent = "start-failed"
+	containerEventExitSuccess containerEvent = "exit-success"
+	containerEventExitError   containerEvent = "exit-error"
+)
+
+// poolEvent is how a poolItem reports eventItemReady/eventItemExit back
+// to the Pool that created it, over the channel it Join()s.
+type poolEventID string
+
+const (
+	eventItemReady poolEventID = "ready"
+	eventItemExit  poolEventID = "exit"
+)
+
+type poolEvent struct {
+	id   poolEventID
+	item PoolItem
+}
+
+// StatusItem is the read-only view of a running container a lifecycle
+// action or Provisioner gets: enough to identify it and read back its
+// published ports (see TCPPortsFor), nothing that would let it drive
+// the container directly.
+type StatusItem interface {
+	ID() string
+	Status() types.ContainerJSON
+}
+
+// PoolContainer is the container backing a single pool item. item.go
+// drives it through Start/Stop and watches Events() for state changes;
+// it doesn't know or care which Adapter created it.
+type PoolContainer interface {
+	StatusItem
+	Start()
+	Stop()
+	Events() <-chan containerEvent
+}
+
+// Adapter creates and drives containers for a pool on a specific
+// container runtime, and builds the connection params callers need
+// once one is up. backend/docker, backend/containerd, and
+// backend/podman each implement this against their own execution API;
+// which one a pool uses is picked by its config's "backend" key (see
+// package backend).
+type Adapter interface {
+	Create(ctx context.Context, ref string) (string, error)
+	Start(ctx context.Context, id string) error
+	Stop(ctx context.Context, id string) error
+	Wait(ctx context.Context, id string) error
+	Status(ctx context.Context, id string) (types.ContainerJSON, error)
+
+	MakeParams(c PoolContainer) (params.Params, error)
+}
+
+// lcid tags log with a short container-id field, trimmed the same way
+// the docker CLI shortens ids, so adjacent log lines for different
+// containers stay easy to tell apart without the full id.
+func lcid(log logrus.FieldLogger, id string) logrus.FieldLogger {
+	short := id
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	return log.WithField("container", short)
+}
+
+// TCPPortsFor extracts the host-side port for each published TCP
+// container port from a docker-shaped container inspect result, keyed by
+// container port number without the protocol (e.g. "8200"). backend/podman
+// reports its published ports in this shape via its own Adapter.Status;
+// backend/containerd's Status reports none, so this returns an empty map
+// for its containers.
+func TCPPortsFor(c types.ContainerJSON) map[string]string {
+	out := make(map[string]string)
+	if c.NetworkSettings == nil {
+		return out
+	}
+	for port, bindings := range c.NetworkSettings.Ports {
+		if port.Proto() != "tcp" || len(bindings) == 0 {
+			continue
+		}
+		out[port.Port()] = bindings[0].HostPort
+	}
+	return out
+}
+
+func firstTCPParams(c PoolContainer, status types.ContainerJSON, backend string) (params.Params, error) {
+	ports := TCPPortsFor(status)
+	if len(ports) == 0 {
+		return params.Params{}, fmt.Errorf("%v: no published ports for %v", backend, c.ID())
+	}
+
+	keys := make([]string, 0, len(ports))
+	for k := range ports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return params.Params{Url: fmt.Sprintf("tcp://localhost:%v", ports[keys[0]])}, nil
+}
+
+// poolContainer is the concrete PoolContainer createPoolContainer
+// hands to every poolItem: it just drives whatever Adapter it was given
+// through Create/Start/Wait and republishes the result as
+// containerEvent traffic, so item.go stays backend-neutral.
+type poolContainer struct {
+	adapter Adapter
+	id      string
+
+	eventsch chan containerEvent
+}
+
+// createPoolContainer creates (but doesn't start) a container for image
+// via adapter.
+func createPoolContainer(ctx context.Context, adapter Adapter, image string) (PoolContainer, error) {
+	id, err := adapter.Create(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poolContainer{
+		adapter:  adapter,
+		id:       id,
+		eventsch: make(chan containerEvent, 4),
+	}, nil
+}
+
+func (c *poolContainer) ID() string { return c.id }
+
+func (c *poolContainer) Status() types.ContainerJSON {
+	status, err := c.adapter.Status(context.Background(), c.id)
+	if err != nil {
+		return types.ContainerJSON{}
+	}
+	return status
+}
+
+func (c *poolContainer) Events() <-chan containerEvent { return c.eventsch }
+
+// Start runs the container and waits for it to exit in the background,
+// reporting both as containerEvent traffic on Events().
+func (c *poolContainer) Start() {
+	go func() {
+		ctx := context.Background()
+
+		if err := c.adapter.Start(ctx, c.id); err != nil {
+			c.eventsch <- containerEventStartFailed
+			return
+		}
+		c.eventsch <- containerEventStarted
+
+		if err := c.adapter.Wait(ctx, c.id); err != nil {
+			c.eventsch <- containerEventExitError
+			return
+		}
+		c.eventsch <- containerEventExitSuccess
+	}()
+}
+
+func (c *poolContainer) Stop() {
+	c.adapter.Stop(context.Background(), c.id)
+}
+
+// Config is the programmatic pool configuration NewPool takes, built
+// with NewConfig and its chainable With*/ExposePort methods. It's the
+// counterpart to config.Config for callers that build a pool in Go
+// instead of from a JSON file (see builtin/vault.Builder).
+type Config struct {
+	image string
+	ports []exposedPort
+	env   map[string]string
+}
+
+type exposedPort struct {
+	proto string
+	port  int
+}
+
+// NewConfig returns an empty Config ready for WithImage/ExposePort/WithEnv.
+func NewConfig() *Config {
+	return &Config{env: make(map[string]string)}
+}
+
+// WithImage sets the image each of the pool's containers is created from.
+func (c *Config) WithImage(image string) *Config {
+	c.image = image
+	return c
+}
+
+// ExposePort records a port the pool's containers publish, so Adapter.MakeParams
+// can find it afterwards via TCPPortsFor.
+func (c *Config) ExposePort(proto string, port int) *Config {
+	c.ports = append(c.ports, exposedPort{proto, port})
+	return c
+}
+
+// WithEnv sets an environment variable passed to each of the pool's containers.
+func (c *Config) WithEnv(key, value string) *Config {
+	c.env[key] = value
+	return c
+}
+
+// ProvisionFn is a single healthcheck/initialize/reset step run against
+// a pool item's StatusItem view. It's the programmatic counterpart of a
+// lifecycle.Action, for callers that build a Provisioner in Go (see
+// ProvisionerBuilder) instead of writing a JSON pool config.
+type ProvisionFn func(context.Context, StatusItem) error
+
+// Provisioner drives a pool item through its healthcheck/initialize/
+// reset stages; it's the same interface a JSON-driven pool uses
+// (lifecycle.Manager), since NewPool wires either one into
+// createPoolItem identically.
+type Provisioner = lifecycle.Manager
+
+// ProvisionerBuilder builds a Provisioner out of ProvisionFns, for
+// NewPool callers that would rather write Go than a JSON pool config.
+type ProvisionerBuilder interface {
+	WithLiveCheck(ProvisionFn) ProvisionerBuilder
+	WithInitialize(ProvisionFn) ProvisionerBuilder
+	WithReset(ProvisionFn) ProvisionerBuilder
+	Create() Provisioner
+}
+
+type provisionerBuilder struct {
+	liveCheck  ProvisionFn
+	initialize ProvisionFn
+	reset      ProvisionFn
+}
+
+// BuildProvisioner returns an empty ProvisionerBuilder.
+func BuildProvisioner() ProvisionerBuilder {
+	return &provisionerBuilder{}
+}
+
+func (b *provisionerBuilder) WithLiveCheck(fn ProvisionFn) ProvisionerBuilder {
+	b.liveCheck = fn
+	return b
+}
+
+func (b *provisionerBuilder) WithInitialize(fn ProvisionFn) ProvisionerBuilder {
+	b.initialize = fn
+	return b
+}
+
+func (b *provisionerBuilder) WithReset(fn ProvisionFn) ProvisionerBuilder {
+	b.reset = fn
+	return b
+}
+
+func (b *provisionerBuilder) Create() Provisioner {
+	return &provisioner{liveCheck: b.liveCheck, initialize: b.initialize, reset: b.reset}
+}
+
+// provisioner adapts a Go-built Provisioner onto the lifecycle.Manager
+// interface createPoolItem expects. Unlike a JSON-driven Manager (which
+// only ever sees params.Params), a ProvisionFn needs the item's full
+// StatusItem view (see vault.Item, which reads ports back off it) -- so
+// createPoolItem binds item to the pool item's own container once it
+// exists, via bindStatusItem, rather than going through
+// ForContainer's bare id.
+type provisioner struct {
+	liveCheck  ProvisionFn
+	initialize ProvisionFn
+	reset      ProvisionFn
+	item       StatusItem
+}
+
+func (p *provisioner) ForContainer(id string) lifecycle.Manager {
+	return p
+}
+
+func (p *provisioner) bindStatusItem(item StatusItem) lifecycle.Manager {
+	return &provisioner{liveCheck: p.liveCheck, initialize: p.initialize, reset: p.reset, item: item}
+}
+
+func (p *provisioner) HasHealthcheck() bool { return p.liveCheck != nil }
+func (p *provisioner) DoHealthcheck(ctx context.Context, _ params.Params) error {
+	return p.liveCheck(ctx, p.item)
+}
+
+func (p *provisioner) HasInitialize() bool { return p.initialize != nil }
+func (p *provisioner) DoInitialize(ctx context.Context, _ params.Params) error {
+	return p.initialize(ctx, p.item)
+}
+
+func (p *provisioner) HasReset() bool { return p.reset != nil }
+func (p *provisioner) DoReset(ctx context.Context, _ params.Params) error {
+	return p.reset(ctx, p.item)
+}
+
+// statusItemBinder is implemented only by *provisioner; createPoolItem
+// uses it to bind a Go-built Provisioner to the item's container once
+// it exists. It's intentionally not part of lifecycle.Manager, since a
+// JSON-driven Manager has no equivalent concept.
+type statusItemBinder interface {
+	bindStatusItem(StatusItem) lifecycle.Manager
+}
+
+const (
+	// LiveCheckDefaultTimeout bounds a single attempt made by LiveCheck.
+	LiveCheckDefaultTimeout = 5 * time.Second
+	// LiveCheckDefaultRetries is how many attempts LiveCheck makes before giving up.
+	LiveCheckDefaultRetries = 10
+	// LiveCheckDefaultDelay is how long LiveCheck waits between attempts.
+	LiveCheckDefaultDelay = time.Second
+)
+
+// LiveCheck wraps fn with a bounded retry loop, for a ProvisionFn that
+// needs to poll until a container is actually reachable rather than
+// just started (see vault.LiveCheck).
+func LiveCheck(timeout time.Duration, tries int, delay time.Duration, fn ProvisionFn) ProvisionFn {
+	return func(ctx context.Context, si StatusItem) error {
+		var err error
+
+		for i := 0; i < tries; i++ {
+			cctx, cancel := context.WithTimeout(ctx, timeout)
+			err = fn(cctx, si)
+			cancel()
+
+			if err == nil {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		return err
+	}
+}
+
+// PoolItem is a single container managed by a Pool: item.go's poolItem
+// is the only implementation.
+type PoolItem interface {
+	StatusItem
+
+	State() string
+	Params() (params.Params, error)
+
+	Join(chan<- poolEvent)
+	Start()
+	Reset()
+	Kill()
+
+	LeaseExpired()
+	Checkout() LeaseID
+	ExtendLease(LeaseID) error
+	ReleaseLease(LeaseID)
+}
+
+// PoolEvent is a coarse state-change notification for a single pool
+// item, delivered by Pool.Watch/PoolSet.WatchAll (and, over the wire,
+// net/rpc's Watch/Events streams). It doesn't carry per-action
+// lifecycle detail -- that's only visible to the ui package's own event
+// processor -- just enough for an external watcher to know an item
+// became ready or exited.
+type PoolEvent struct {
+	PoolName string
+	ID       string
+	ItemID   string
+
+	LifecycleName  string
+	ActionName     string
+	ActionAttempt  int
+	ActionAttempts int
+	Err            error
+}
+
+// Pool manages a fixed-size set of interchangeable containers, handing
+// them out via Checkout and reclaiming them (via Return, or the lease
+// reaper on an unrenewed checkout) for the next caller.
+type Pool interface {
+	// Checkout hands out a ready item along with the id of the checkout
+	// lease created for it; the caller must renew the lease (directly,
+	// or via ExtendLease/the HTTP handler in net/lease.go) before it
+	// expires, or the item will be reclaimed out from under it.
+	Checkout() (PoolItem, LeaseID, error)
+	Return(PoolItem)
+
+	WaitReady() error
+	Stop() error
+
+	Item(id string) (PoolItem, error)
+	Items() []PoolItem
+
+	// Watch streams ready/exit notifications for this pool's items
+	// until the returned cancel func is called.
+	Watch() (<-chan PoolEvent, func())
+}
+
+type pool struct {
+	name      string
+	log       logrus.FieldLogger
+	adapter   Adapter
+	lifecycle lifecycle.Manager
+	image     string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	items   map[string]PoolItem
+	leaseOf map[string]LeaseID
+
+	free chan PoolItem
+
+	readyWG sync.WaitGroup
+
+	eventsch chan poolEvent
+
+	subsMu sync.Mutex
+	subs   map[chan PoolEvent]bool
+}
+
+func newPool(ctx context.Context, log logrus.FieldLogger, name string, size int, adapter Adapter, lm lifecycle.Manager, image string) (*pool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &pool{
+		name:      name,
+		log:       log.WithField("pool", name),
+		adapter:   adapter,
+		lifecycle: lm,
+		image:     image,
+		ctx:       ctx,
+		cancel:    cancel,
+		items:     make(map[string]PoolItem),
+		leaseOf:   make(map[string]LeaseID),
+		free:      make(chan PoolItem, size),
+		eventsch:  make(chan poolEvent),
+		subs:      make(map[chan PoolEvent]bool),
+	}
+
+	p.readyWG.Add(size)
+
+	for i := 0; i < size; i++ {
+		item, err := createPoolItem(p.log, adapter, lm, image)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		p.items[item.ID()] = item
+		item.Join(p.eventsch)
+		item.Start()
+	}
+
+	go p.run()
+
+	return p, nil
+}
+
+func (p *pool) run() {
+	pending := make(map[string]bool, len(p.items))
+	p.mu.Lock()
+	for id := range p.items {
+		pending[id] = true
+	}
+	p.mu.Unlock()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case e := <-p.eventsch:
+			switch e.id {
+			case eventItemReady:
+				select {
+				case p.free <- e.item:
+				default:
+				}
+
+				if pending[e.item.ID()] {
+					delete(pending, e.item.ID())
+					p.readyWG.Done()
+				}
+
+				p.broadcast(PoolEvent{PoolName: p.name, ID: string(eventItemReady), ItemID: e.item.ID()})
+			case eventItemExit:
+				p.mu.Lock()
+				delete(p.items, e.item.ID())
+				p.mu.Unlock()
+
+				p.broadcast(PoolEvent{PoolName: p.name, ID: string(eventItemExit), ItemID: e.item.ID()})
+			}
+		}
+	}
+}
+
+func (p *pool) broadcast(e PoolEvent) {
+	p.subsMu.Lock()
+	defer p.subsMu.Unlock()
+
+	for ch := range p.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (p *pool) WaitReady() error {
+	done := make(chan struct{})
+	go func() {
+		p.readyWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+func (p *pool) Checkout() (PoolItem, LeaseID, error) {
+	select {
+	case item := <-p.free:
+		id := item.Checkout()
+
+		p.mu.Lock()
+		p.leaseOf[item.ID()] = id
+		p.mu.Unlock()
+
+		return item, id, nil
+	case <-p.ctx.Done():
+		return nil, "", p.ctx.Err()
+	}
+}
+
+func (p *pool) Return(item PoolItem) {
+	p.mu.Lock()
+	id, ok := p.leaseOf[item.ID()]
+	delete(p.leaseOf, item.ID())
+	p.mu.Unlock()
+
+	if ok {
+		item.ReleaseLease(id)
+	}
+
+	item.Reset()
+}
+
+func (p *pool) Stop() error {
+	p.cancel()
+
+	p.mu.Lock()
+	items := make([]PoolItem, 0, len(p.items))
+	for _, item := range p.items {
+		items = append(items, item)
+	}
+	p.mu.Unlock()
+
+	for _, item := range items {
+		item.Kill()
+	}
+
+	return nil
+}
+
+func (p *pool) Item(id string) (PoolItem, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	item, ok := p.items[id]
+	if !ok {
+		return nil, fmt.Errorf("ephemerald: item %v not found", id)
+	}
+	return item, nil
+}
+
+func (p *pool) Items() []PoolItem {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]PoolItem, 0, len(p.items))
+	for _, item := range p.items {
+		out = append(out, item)
+	}
+	return out
+}
+
+func (p *pool) Watch() (<-chan PoolEvent, func()) {
+	ch := make(chan PoolEvent, 16)
+
+	p.subsMu.Lock()
+	p.subs[ch] = true
+	p.subsMu.Unlock()
+
+	cancel := func() {
+		p.subsMu.Lock()
+		if p.subs[ch] {
+			delete(p.subs, ch)
+			close(ch)
+		}
+		p.subsMu.Unlock()
+	}
+
+	return ch, cancel
+}
+
+// NewPool starts size containers from cfg using provisioner and returns
+// a Pool once they've been created (not necessarily ready -- call
+// WaitReady for that).
+//
+// It always uses the Docker backend directly rather than going through
+// package backend's pluggable Adapter selection: backend/docker,
+// backend/containerd, and backend/podman all import this package for
+// Adapter/PoolContainer, so this package importing any of them back
+// would be a cycle. Only config-driven pools built via NewPoolSet/
+// NewPoolFromConfig can pick a different backend, via the pool config's
+// "backend" key.
+func NewPool(cfg *Config, size int, provisioner Provisioner) (Pool, error) {
+	log := logrus.StandardLogger().WithField("component", "pool")
+
+	adapter, err := NewDockerAdapter(log)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPool(context.Background(), log, "", size, adapter, provisioner, cfg.image)
+}
+
+// NewPoolFromConfig starts a pool from a parsed JSON pool config (see
+// config.Parse), using adapter to create its containers. Callers
+// resolve cfg.Backend to an Adapter themselves (see package backend)
+// before calling this, since this package can't import the packages
+// that implement Adapter without an import cycle.
+func NewPoolFromConfig(cfg *config.Config, adapter Adapter) (Pool, error) {
+	log := logrus.StandardLogger().WithField("component", "pool")
+	return newPool(context.Background(), log, cfg.Name, cfg.Size, adapter, cfg.Lifecycle, cfg.Image)
+}
+
+// PoolConfig pairs a parsed pool config with the Adapter resolved for
+// its Backend, for NewPoolSet.
+type PoolConfig struct {
+	Config  *config.Config
+	Adapter Adapter
+}
+
+// PoolSet manages every pool ephemerald is running.
+type PoolSet interface {
+	// Pool returns the named pool, or nil if it's not part of the set.
+	Pool(name string) Pool
+	// WatchAll streams ready/exit notifications for every pool in the
+	// set until the returned cancel func is called.
+	WatchAll() (<-chan PoolEvent, func())
+	Stop() error
+}
+
+type poolSet struct {
+	mu    sync.Mutex
+	pools map[string]Pool
+
+	subsMu sync.Mutex
+	subs   map[chan PoolEvent]bool
+
+	donech chan struct{}
+}
+
+// NewPoolSet starts one pool per entry in configs, using the paired
+// Adapter to create that pool's containers.
+func NewPoolSet(log logrus.FieldLogger, ctx context.Context, configs []PoolConfig) (PoolSet, error) {
+	ps := &poolSet{
+		pools:  make(map[string]Pool),
+		subs:   make(map[chan PoolEvent]bool),
+		donech: make(chan struct{}),
+	}
+
+	for _, pc := range configs {
+		p, err := newPool(ctx, log, pc.Config.Name, pc.Config.Size, pc.Adapter, pc.Config.Lifecycle, pc.Config.Image)
+		if err != nil {
+			ps.Stop()
+			return nil, err
+		}
+
+		ps.pools[pc.Config.Name] = p
+
+		events, cancel := p.Watch()
+		go ps.relay(events, cancel)
+	}
+
+	return ps, nil
+}
+
+func (ps *poolSet) relay(events <-chan PoolEvent, cancel func()) {
+	defer cancel()
+
+	for {
+		select {
+		case <-ps.donech:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			ps.broadcast(e)
+		}
+	}
+}
+
+func (ps *poolSet) broadcast(e PoolEvent) {
+	ps.subsMu.Lock()
+	defer ps.subsMu.Unlock()
+
+	for ch := range ps.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func (ps *poolSet) Pool(name string) Pool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.pools[name]
+}
+
+func (ps *poolSet) WatchAll() (<-chan PoolEvent, func()) {
+	ch := make(chan PoolEvent, 32)
+
+	ps.subsMu.Lock()
+	ps.subs[ch] = true
+	ps.subsMu.Unlock()
+
+	cancel := func() {
+		ps.subsMu.Lock()
+		if ps.subs[ch] {
+			delete(ps.subs, ch)
+			close(ch)
+		}
+		ps.subsMu.Unlock()
+	}
+
+	return ch, cancel
+}
+
+func (ps *poolSet) Stop() error {
+	close(ps.donech)
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, p := range ps.pools {
+		p.Stop()
+	}
+	return nil
+}
@@ -1,13 +1,18 @@
 package testutil
 
 import (
+	"bytes"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/boz/ephemerald"
+	"github.com/boz/ephemerald/backend"
 	"github.com/boz/ephemerald/config"
 	"github.com/boz/ephemerald/params"
 	"github.com/boz/ephemerald/ui"
@@ -33,18 +38,53 @@ func ContainerEmitter() ui.ContainerEmitter {
 
 func RunPoolFromFile(t *testing.T, path string, fn func(params.Params)) {
 	WithPoolFromFile(t, path, func(pool ephemerald.Pool) {
-		item, err := pool.Checkout()
+		item, _, err := pool.Checkout()
+		require.NoError(t, err)
+		assert.NotNil(t, item)
+
+		p, err := item.Params()
 		require.NoError(t, err)
 
 		if fn != nil {
-			fn(item)
+			fn(p)
 		}
 
-		assert.NotNil(t, item)
 		pool.Return(item)
 	})
 }
 
+// ExtendLease periodically renews leaseID against an ephemerald server's
+// lease endpoint (POST {addr}/lease/extend, which ephemerald/main.go
+// serves on its --metrics-port listener), for integration tests
+// that check an item out over HTTP and run a slow fn against it: without
+// renewal the server's lease reaper would reclaim the item out from under
+// the test. Call the returned stop func when fn is done.
+func ExtendLease(addr string, leaseID string, interval time.Duration) (stop func()) {
+	donech := make(chan bool)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-donech:
+				return
+			case <-ticker.C:
+				body, _ := json.Marshal(struct {
+					LeaseID string `json:"lease_id"`
+				}{leaseID})
+				resp, err := http.Post(addr+"/lease/extend", "application/json", bytes.NewReader(body))
+				if err == nil {
+					resp.Body.Close()
+				}
+			}
+		}
+	}()
+
+	return func() { close(donech) }
+}
+
 func WithPoolFromFile(t *testing.T, basename string, fn func(ephemerald.Pool)) {
 
 	path := path.Join("_testdata", basename)
@@ -58,7 +98,10 @@ func WithPoolFromFile(t *testing.T, basename string, fn func(ephemerald.Pool)) {
 	config, err := config.Parse(log, Emitter(), t.Name(), buf)
 	require.NoError(t, err)
 
-	pool, err := ephemerald.NewPool(config)
+	adapter, err := backend.Select(log, config.Backend)
+	require.NoError(t, err)
+
+	pool, err := ephemerald.NewPoolFromConfig(config, adapter)
 	require.NoError(t, err)
 
 	defer func() {
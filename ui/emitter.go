@@ -0,0 +1,39 @@
+package ui
+
+// Emitter is the top-level event sink handed to pool construction (see
+// config.Parse/config.Read), so every pool and container it builds has
+// somewhere to report state changes via ForPool/ForContainer.
+//
+// Wiring ForPool/ForContainer's output into the processor that actually
+// drives a UI's pool/container views is tracked with the rest of that
+// UI, not here.
+type Emitter interface {
+	ForPool(name string) PoolEmitter
+}
+
+// PoolEmitter scopes an Emitter to a single pool.
+type PoolEmitter interface {
+	ForContainer(id string) ContainerEmitter
+}
+
+// ContainerEmitter scopes a PoolEmitter to a single container.
+type ContainerEmitter interface {
+}
+
+type noopEmitter struct{}
+type noopPoolEmitter struct{}
+type noopContainerEmitter struct{}
+
+// NewNoopEmitter returns an Emitter that discards everything, for tests
+// and tools that don't need a live UI.
+func NewNoopEmitter() Emitter {
+	return noopEmitter{}
+}
+
+func (noopEmitter) ForPool(name string) PoolEmitter {
+	return noopPoolEmitter{}
+}
+
+func (noopPoolEmitter) ForContainer(id string) ContainerEmitter {
+	return noopContainerEmitter{}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonEvent is the line format emitted by jsonWriter: one JSON object per
+// pool or container update, carrying enough of pevent/cevent for a log
+// aggregator to reconstruct pool and container lifecycle without scraping
+// the terminal GUI.
+type jsonEvent struct {
+	Time string `json:"time"`
+
+	Pool string `json:"pool"`
+	Kind string `json:"kind"` // "pool" or "container"
+
+	ContainerID string `json:"container_id,omitempty"`
+	State       string `json:"state"`
+	Err         string `json:"error,omitempty"`
+
+	NumItems   int `json:"num_items,omitempty"`
+	NumPending int `json:"num_pending,omitempty"`
+	NumReady   int `json:"num_ready,omitempty"`
+
+	LifecycleName  string `json:"lifecycle_name,omitempty"`
+	ActionName     string `json:"action_name,omitempty"`
+	ActionAttempt  int    `json:"action_attempt,omitempty"`
+	ActionAttempts int    `json:"action_attempts,omitempty"`
+}
+
+type jsonWriter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *jsonWriter) write(e jsonEvent) {
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// best-effort: a log sink shouldn't crash the pool it's reporting on.
+	w.enc.Encode(e)
+}
+
+func (w *jsonWriter) updatePool(p pool) {
+	w.write(jsonEvent{
+		Pool:       p.name,
+		Kind:       "pool",
+		State:      string(p.state),
+		Err:        errString(p.err),
+		NumItems:   p.numItems,
+		NumPending: p.numPending,
+		NumReady:   p.numReady,
+	})
+}
+
+func (w *jsonWriter) updateContainer(c container) {
+	w.write(jsonEvent{
+		Pool:           c.pname,
+		Kind:           "container",
+		ContainerID:    c.id,
+		State:          string(c.state),
+		LifecycleName:  c.lifecycleName,
+		ActionName:     c.actionName,
+		ActionAttempt:  c.actionAttempt,
+		ActionAttempts: c.actionAttempts,
+		Err:            errString(c.actionError),
+	})
+}
+
+func (w *jsonWriter) deleteContainer(c container) {
+	w.write(jsonEvent{
+		Pool:        c.pname,
+		Kind:        "container",
+		ContainerID: c.id,
+		State:       "removed",
+	})
+}
+
+func (w *jsonWriter) stop() {
+	if w.closer != nil {
+		w.closer.Close()
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// NewJSONEmitter returns a UI sink that serializes every pool and
+// container update from ui/processor.go as one JSON object per line to w,
+// for CI systems and log aggregators that don't want to scrape the
+// terminal GUI.
+func NewJSONEmitter(w io.Writer) UI {
+	return newUI(newProcessor(newJSONWriter(w)))
+}
+
+// NewNDJSONFileEmitter is NewJSONEmitter backed by the file at path,
+// truncating it if it already exists. The file is closed when the
+// returned UI's Stop is called.
+func NewNDJSONFileEmitter(path string) (UI, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	jw := newJSONWriter(f)
+	jw.closer = f
+
+	return newUI(newProcessor(jw)), nil
+}
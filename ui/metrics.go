@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricPoolNumItems = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ephemerald",
+		Subsystem: "pool",
+		Name:      "num_items",
+		Help:      "Number of items currently tracked by the pool.",
+	}, []string{"pool"})
+
+	metricPoolNumPending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ephemerald",
+		Subsystem: "pool",
+		Name:      "num_pending",
+		Help:      "Number of items in the pool that are not yet ready.",
+	}, []string{"pool"})
+
+	metricPoolNumReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ephemerald",
+		Subsystem: "pool",
+		Name:      "num_ready",
+		Help:      "Number of items in the pool that are ready for checkout.",
+	}, []string{"pool"})
+
+	metricPoolInitErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ephemerald",
+		Subsystem: "pool",
+		Name:      "init_errors_total",
+		Help:      "Number of pool initialization errors.",
+	}, []string{"pool"})
+
+	metricContainerState = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ephemerald",
+		Subsystem: "container",
+		Name:      "state_transitions_total",
+		Help:      "Number of container lifecycle state transitions, by state.",
+	}, []string{"pool", "state"})
+
+	metricActionAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ephemerald",
+		Subsystem: "container",
+		Name:      "action_attempts_total",
+		Help:      "Number of lifecycle action attempts, by lifecycle and action name.",
+	}, []string{"pool", "lifecycle", "action"})
+
+	metricActionResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ephemerald",
+		Subsystem: "container",
+		Name:      "action_results_total",
+		Help:      "Number of lifecycle action results, by lifecycle, action name, and error class.",
+	}, []string{"pool", "lifecycle", "action", "error"})
+
+	// ActionLatency is recorded around each individual lifecycle action
+	// (not the healthcheck/initialize/reset stage as a whole) so operators
+	// can alert on one specific action being the slow or stuck one when a
+	// stage configures several.
+	ActionLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "ephemerald",
+		Subsystem: "container",
+		Name:      "action_duration_seconds",
+		Help:      "Duration of individual lifecycle actions, by lifecycle and action name.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"pool", "lifecycle", "action"})
+)
+
+// actionStarts tracks when each in-flight action began, so the
+// ceventResult that eventually follows a ceventAction can compute its
+// duration. Keyed by containerId+lifecycleName+actionName, since that's
+// the only thing that ties an attempt event to its result event.
+var (
+	actionStartsMu sync.Mutex
+	actionStarts   = make(map[string]time.Time)
+)
+
+func actionStartKey(e cevent) string {
+	return strings.Join([]string{e.containerId, e.lifecycleName, e.actionName}, "/")
+}
+
+// errorClass buckets an error into a small, fixed set of Prometheus label
+// values instead of using its message directly: error strings from HTTP
+// clients routinely embed container IDs, URLs, or response bodies, and
+// labeling a counter with that would grow its cardinality without bound.
+func errorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "timeout"), strings.Contains(msg, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(msg, "refused"):
+		return "refused"
+	case strings.Contains(msg, "reset by peer"), strings.Contains(msg, "eof"):
+		return "connection-closed"
+	default:
+		return "other"
+	}
+}
+
+func init() {
+	prometheus.MustRegister(
+		metricPoolNumItems,
+		metricPoolNumPending,
+		metricPoolNumReady,
+		metricPoolInitErrors,
+		metricContainerState,
+		metricActionAttempts,
+		metricActionResults,
+		ActionLatency,
+	)
+}
+
+func metricsHandlePoolEvent(e pevent) {
+	switch e.id {
+	case peventInitErr:
+		metricPoolInitErrors.WithLabelValues(e.poolName).Inc()
+	case peventNumItems:
+		metricPoolNumItems.WithLabelValues(e.poolName).Set(float64(e.count))
+	case peventNumPending:
+		metricPoolNumPending.WithLabelValues(e.poolName).Set(float64(e.count))
+	case peventNumReady:
+		metricPoolNumReady.WithLabelValues(e.poolName).Set(float64(e.count))
+	}
+}
+
+func metricsHandleContainerEvent(e cevent) {
+	switch e.id {
+	case ceventCreated, ceventStarted, ceventLive, ceventReady, ceventResetting, ceventExiting, ceventExited:
+		metricContainerState.WithLabelValues(e.poolName, string(e.id)).Inc()
+	case ceventAction:
+		metricActionAttempts.WithLabelValues(e.poolName, e.lifecycleName, e.actionName).Inc()
+
+		actionStartsMu.Lock()
+		actionStarts[actionStartKey(e)] = time.Now()
+		actionStartsMu.Unlock()
+	case ceventResult:
+		metricActionResults.WithLabelValues(e.poolName, e.lifecycleName, e.actionName, errorClass(e.err)).Inc()
+
+		key := actionStartKey(e)
+		actionStartsMu.Lock()
+		start, ok := actionStarts[key]
+		if ok {
+			delete(actionStarts, key)
+		}
+		actionStartsMu.Unlock()
+
+		if ok {
+			ActionLatency.WithLabelValues(e.poolName, e.lifecycleName, e.actionName).Observe(time.Since(start).Seconds())
+		}
+	}
+}
@@ -131,6 +131,7 @@ func (p *processor) handleContainerEvents() {
 }
 
 func (p *processor) handleContainerUpdate(c *container, e cevent) {
+	metricsHandleContainerEvent(e)
 
 	reset := false
 	exited := false
@@ -195,6 +196,7 @@ func (p *processor) handleContainerCreate(e cevent) {
 }
 
 func (p *processor) handlePoolUpdate(pool *pool, e pevent) {
+	metricsHandlePoolEvent(e)
 
 	switch e.id {
 	case peventInit:
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// sseWriter fans the same jsonEvent stream produced by jsonWriter out to
+// any number of connected HTTP clients over server-sent events, for
+// external dashboards that want to tail ephemerald activity live.
+type sseWriter struct {
+	mu   sync.Mutex
+	subs map[chan jsonEvent]bool
+}
+
+func newSSEWriter() *sseWriter {
+	return &sseWriter{subs: make(map[chan jsonEvent]bool)}
+}
+
+func (w *sseWriter) subscribe() chan jsonEvent {
+	ch := make(chan jsonEvent, pBufSiz)
+
+	w.mu.Lock()
+	w.subs[ch] = true
+	w.mu.Unlock()
+
+	return ch
+}
+
+func (w *sseWriter) unsubscribe(ch chan jsonEvent) {
+	w.mu.Lock()
+	delete(w.subs, ch)
+	w.mu.Unlock()
+	close(ch)
+}
+
+func (w *sseWriter) broadcast(e jsonEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs {
+		select {
+		case ch <- e:
+		default:
+			// slow subscriber: drop the event rather than block the pool.
+		}
+	}
+}
+
+func (w *sseWriter) updatePool(p pool) {
+	w.broadcast(jsonEvent{
+		Pool:       p.name,
+		Kind:       "pool",
+		State:      string(p.state),
+		Err:        errString(p.err),
+		NumItems:   p.numItems,
+		NumPending: p.numPending,
+		NumReady:   p.numReady,
+	})
+}
+
+func (w *sseWriter) updateContainer(c container) {
+	w.broadcast(jsonEvent{
+		Pool:           c.pname,
+		Kind:           "container",
+		ContainerID:    c.id,
+		State:          string(c.state),
+		LifecycleName:  c.lifecycleName,
+		ActionName:     c.actionName,
+		ActionAttempt:  c.actionAttempt,
+		ActionAttempts: c.actionAttempts,
+		Err:            errString(c.actionError),
+	})
+}
+
+func (w *sseWriter) deleteContainer(c container) {
+	w.broadcast(jsonEvent{
+		Pool:        c.pname,
+		Kind:        "container",
+		ContainerID: c.id,
+		State:       "removed",
+	})
+}
+
+func (w *sseWriter) stop() {}
+
+// fanoutWriter forwards every update to multiple writers, so a single
+// processor can drive both a JSON log sink and an SSE broadcaster off the
+// same event stream.
+type fanoutWriter struct {
+	writers []interface {
+		updatePool(pool)
+		updateContainer(container)
+		deleteContainer(container)
+		stop()
+	}
+}
+
+func (f fanoutWriter) updatePool(p pool) {
+	for _, w := range f.writers {
+		w.updatePool(p)
+	}
+}
+
+func (f fanoutWriter) updateContainer(c container) {
+	for _, w := range f.writers {
+		w.updateContainer(c)
+	}
+}
+
+func (f fanoutWriter) deleteContainer(c container) {
+	for _, w := range f.writers {
+		w.deleteContainer(c)
+	}
+}
+
+func (f fanoutWriter) stop() {
+	for _, w := range f.writers {
+		w.stop()
+	}
+}
+
+// NewJSONEmitterWithSSE is NewJSONEmitter, plus an SSEHandler fed from the
+// same event stream so a single --ui=json process can serve both a log
+// file and a live dashboard.
+func NewJSONEmitterWithSSE(w io.Writer) (UI, *SSEHandler) {
+	jw := newJSONWriter(w)
+	sw := newSSEWriter()
+
+	u := newUI(newProcessor(fanoutWriter{writers: []interface {
+		updatePool(pool)
+		updateContainer(container)
+		deleteContainer(container)
+		stop()
+	}{jw, sw}}))
+
+	return u, &SSEHandler{ui: u, w: sw}
+}
+
+// SSEHandler tails ephemerald's pool/container activity as a
+// text/event-stream, one JSON-encoded jsonEvent per event, for external
+// dashboards.
+type SSEHandler struct {
+	ui UI
+	w  *sseWriter
+}
+
+func (h *SSEHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+
+	ch := h.w.subscribe()
+	defer h.w.unsubscribe(ch)
+
+	enc := json.NewEncoder(rw)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			rw.Write([]byte("data: "))
+			enc.Encode(e)
+			rw.Write([]byte("\n"))
+			flusher.Flush()
+		}
+	}
+}